@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/lissy93/who-dat/lib"
+)
+
+// AvailabilityHandler reports whether each requested domain is registered,
+// available, or reserved, e.g. GET /api/available?domains=example.com,foo.io
+func AvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Please use a GET request", http.StatusMethodNotAllowed)
+		return
+	}
+
+	domainsQuery := r.URL.Query().Get("domains")
+	if domainsQuery == "" {
+		http.Error(w, "No domains specified", http.StatusBadRequest)
+		return
+	}
+	domains := splitDomains(domainsQuery)
+
+	ctx, cancel := context.WithTimeout(context.Background(), parseTimeoutParam(r.URL.Query().Get("timeout"), false))
+	defer cancel()
+
+	results, err := checkMultiAvailability(ctx, domains)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, results)
+}
+
+// checkMultiAvailability checks availability for a batch of domains
+// concurrently. A failed lookup for one domain is recorded in that
+// domain's AvailabilityInfo.Error rather than aborting the rest of the
+// batch. It always waits for every goroutine to finish writing its slot
+// before returning - ctx cancellation is still surfaced via the returned
+// error, but only after every in-flight lib.CheckAvailability call has
+// returned, so there's no race between an early return and in-flight writes
+// to results. CheckAvailability observes ctx only while resolving the server
+// and waiting on the rate limiter; the Whois query itself is not cancelled.
+func checkMultiAvailability(ctx context.Context, domains []string) ([]lib.AvailabilityInfo, error) {
+	results := make([]lib.AvailabilityInfo, len(domains))
+
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func(i int, domain string) {
+			defer wg.Done()
+
+			info, err := lib.CheckAvailability(ctx, domain)
+			if err != nil {
+				info = lib.AvailabilityInfo{Domain: domain, Error: err.Error()}
+			}
+			results[i] = info
+		}(i, domain)
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
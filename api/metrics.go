@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lissy93/who-dat/lib"
+)
+
+const (
+	// metricsDomainsEnv lists the domains to watch, comma separated
+	metricsDomainsEnv = "WHODAT_METRICS_DOMAINS"
+	// metricsIntervalEnv overrides how often watched domains are refreshed
+	metricsIntervalEnv = "WHODAT_METRICS_INTERVAL"
+	// defaultMetricsInterval is used when metricsIntervalEnv is unset or invalid
+	defaultMetricsInterval = 10 * time.Minute
+	// refreshPerDomainTimeout bounds a single domain's lookup during a
+	// refresh, so one hanging registry can't stall the rest of the watchlist
+	refreshPerDomainTimeout = 15 * time.Second
+)
+
+// domainMetrics holds the last known Whois-derived stats for a watched domain
+type domainMetrics struct {
+	whois           lib.WhoisInfo
+	querySuccess    bool
+	queryDuration   time.Duration
+	lastRefreshedAt time.Time
+}
+
+// metricsStore caches the latest domainMetrics for every watched domain,
+// refreshed in the background so scrapes never trigger a live Whois lookup
+type metricsStore struct {
+	mu      sync.RWMutex
+	domains map[string]domainMetrics
+}
+
+var globalMetricsStore = &metricsStore{domains: make(map[string]domainMetrics)}
+
+// refresherOnce ensures the background refresher is started exactly once,
+// lazily on the first /metrics scrape, since this package has no other
+// process-lifecycle hook to start it from
+var refresherOnce sync.Once
+
+// StartMetricsRefresher launches the background goroutine that keeps the
+// watched domains' metrics warm in globalMetricsStore until ctx is cancelled
+func StartMetricsRefresher(ctx context.Context) {
+	domains := watchedDomains()
+	if len(domains) == 0 {
+		return
+	}
+
+	interval := metricsInterval()
+
+	refreshDomain(ctx, domains)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refreshDomain(ctx, domains)
+			}
+		}
+	}()
+}
+
+// refreshDomain queries every watched domain concurrently, each bounded by
+// refreshPerDomainTimeout, so one slow or hanging registry can't stall the
+// rest of the watchlist or leave their metrics stale
+func refreshDomain(ctx context.Context, domains []string) {
+	var wg sync.WaitGroup
+	for _, domain := range domains {
+		wg.Add(1)
+		go func(domain string) {
+			defer wg.Done()
+
+			domainCtx, cancel := context.WithTimeout(ctx, refreshPerDomainTimeout)
+			defer cancel()
+
+			start := time.Now()
+			whois, err := lib.GetWhoisContext(domainCtx, domain)
+			duration := time.Since(start)
+
+			globalMetricsStore.mu.Lock()
+			globalMetricsStore.domains[domain] = domainMetrics{
+				whois:           whois,
+				querySuccess:    err == nil,
+				queryDuration:   duration,
+				lastRefreshedAt: time.Now(),
+			}
+			globalMetricsStore.mu.Unlock()
+		}(domain)
+	}
+	wg.Wait()
+}
+
+// MetricsHandler exposes Prometheus/OpenMetrics-format metrics for every
+// domain configured via WHODAT_METRICS_DOMAINS
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Please use a GET request", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Lazily start the background refresher on first scrape, and populate
+	// the store synchronously before ever reading it, so /metrics never
+	// reports an empty watchlist just because nothing triggered a refresh yet
+	refresherOnce.Do(func() {
+		StartMetricsRefresher(context.Background())
+	})
+
+	globalMetricsStore.mu.RLock()
+	defer globalMetricsStore.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP whodat_domain_expiry_seconds Unix timestamp when the domain registration expires")
+	fmt.Fprintln(w, "# TYPE whodat_domain_expiry_seconds gauge")
+	for domain, m := range globalMetricsStore.domains {
+		if m.whois.ExpirationDate != nil {
+			fmt.Fprintf(w, "whodat_domain_expiry_seconds{domain=%q} %d\n", domain, m.whois.ExpirationDate.Unix())
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP whodat_domain_age_days Age of the domain in days since creation")
+	fmt.Fprintln(w, "# TYPE whodat_domain_age_days gauge")
+	for domain, m := range globalMetricsStore.domains {
+		fmt.Fprintf(w, "whodat_domain_age_days{domain=%q} %d\n", domain, calculateDomainAge(m.whois.CreatedDate))
+	}
+
+	fmt.Fprintln(w, "# HELP whodat_domain_registration_length_days Length of the domain registration in days")
+	fmt.Fprintln(w, "# TYPE whodat_domain_registration_length_days gauge")
+	for domain, m := range globalMetricsStore.domains {
+		fmt.Fprintf(w, "whodat_domain_registration_length_days{domain=%q} %d\n", domain, calculateDomainRegistrationLength(m.whois.CreatedDate, m.whois.ExpirationDate))
+	}
+
+	fmt.Fprintln(w, "# HELP whodat_whois_query_success Whether the last Whois query for the domain succeeded")
+	fmt.Fprintln(w, "# TYPE whodat_whois_query_success gauge")
+	for domain, m := range globalMetricsStore.domains {
+		fmt.Fprintf(w, "whodat_whois_query_success{domain=%q} %s\n", domain, boolToMetric(m.querySuccess))
+	}
+
+	fmt.Fprintln(w, "# HELP whodat_whois_query_duration_seconds Duration of the last Whois query for the domain")
+	fmt.Fprintln(w, "# TYPE whodat_whois_query_duration_seconds gauge")
+	for domain, m := range globalMetricsStore.domains {
+		fmt.Fprintf(w, "whodat_whois_query_duration_seconds{domain=%q} %f\n", domain, m.queryDuration.Seconds())
+	}
+
+	cacheHits, cacheMisses := lib.CacheStats()
+	fmt.Fprintln(w, "# HELP whodat_cache_hits_total Total number of Whois lookups served from the TTL cache")
+	fmt.Fprintln(w, "# TYPE whodat_cache_hits_total counter")
+	fmt.Fprintf(w, "whodat_cache_hits_total %d\n", cacheHits)
+
+	fmt.Fprintln(w, "# HELP whodat_cache_misses_total Total number of Whois lookups that missed the TTL cache")
+	fmt.Fprintln(w, "# TYPE whodat_cache_misses_total counter")
+	fmt.Fprintf(w, "whodat_cache_misses_total %d\n", cacheMisses)
+}
+
+// watchedDomains reads and normalizes the domain list from metricsDomainsEnv
+func watchedDomains() []string {
+	raw := os.Getenv(metricsDomainsEnv)
+	if raw == "" {
+		return nil
+	}
+
+	var domains []string
+	for _, domain := range strings.Split(raw, ",") {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// metricsInterval reads the refresh interval from metricsIntervalEnv, falling
+// back to defaultMetricsInterval when unset or invalid
+func metricsInterval() time.Duration {
+	raw := os.Getenv(metricsIntervalEnv)
+	if raw == "" {
+		return defaultMetricsInterval
+	}
+
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return defaultMetricsInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// boolToMetric renders a bool as the 1/0 a Prometheus gauge expects
+func boolToMetric(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,19 +33,33 @@ func MultiHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "No domains specified", http.StatusBadRequest)
 		return
 	}
-	domains := strings.Split(domainsQuery, ",")
-
-	// Set up a timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	domains := splitDomains(domainsQuery)
+	ttl := parseTTLParam(r.URL.Query().Get("ttl"))
+	protocol := parseProtocolParam(r.URL.Query().Get("protocol"))
+	stream := r.URL.Query().Get("stream")
+	streaming := stream == streamModeNDJSON || stream == streamModeSSE
+
+	// Set up a timeout context; streaming batches get a much longer default
+	// since checking 500+ domains can take a while even with a worker pool
+	ctx, cancel := context.WithTimeout(context.Background(), parseTimeoutParam(r.URL.Query().Get("timeout"), streaming))
 	defer cancel()
 
-	// Get Whois data for all domains
-	allWhois, err := lib.GetMultiWhois(ctx, domains)
+	if streaming {
+		streamMultiHandler(w, ctx, stream, domains, ttl, protocol)
+		return
+	}
+
+	// Get Whois data for all domains, served from the TTL cache where possible
+	allWhois, hits, err := lib.GetMultiWhoisCached(ctx, domains, ttl, protocol)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	// The response as a whole is only a cache HIT if every domain was
+	// served from cache; see cacheHeaderValue
+	w.Header().Set("X-Whodat-Cache", cacheHeaderValue(hits))
+
 	// Prepare response data with additional domain info
 	var domainInfos []DomainInfo
 	for _, whois := range allWhois {
@@ -57,6 +72,7 @@ func MultiHandler(w http.ResponseWriter, r *http.Request) {
 			WhoisInfo:          whois,
 			DomainAge:          domainAge,
 			DomainRegLength:    regLength,
+			Error:              whois.Error,
 		}
 
 		domainInfos = append(domainInfos, domainInfo)
@@ -66,6 +82,86 @@ func MultiHandler(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, domainInfos)
 }
 
+// splitDomains splits a comma-separated ?domains= value, trimming
+// whitespace around each entry so "a.com, b.com" doesn't produce a
+// lookup for " b.com"
+func splitDomains(raw string) []string {
+	parts := strings.Split(raw, ",")
+	domains := make([]string, len(parts))
+	for i, part := range parts {
+		domains[i] = strings.TrimSpace(part)
+	}
+	return domains
+}
+
+// parseTTLParam parses the ?ttl= query param as seconds, returning 0 (which
+// tells lib to fall back to lib.DefaultCacheTTL) when absent or invalid
+func parseTTLParam(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// defaultTimeout and defaultStreamTimeout bound how long MultiHandler
+// waits for the batch to complete; streaming mode defaults much higher
+// since it's meant for large batches that wouldn't fit in a short window.
+// defaultTimeout in particular has to leave room for lib's per-server rate
+// limiter (serverRateLimitPerSecond/serverRateLimitBurst in lib/ratelimit.go),
+// which serializes same-TLD domains in a batch down to one every ~2s.
+const (
+	defaultTimeout       = 30 * time.Second
+	defaultStreamTimeout = 120 * time.Second
+)
+
+// parseTimeoutParam parses the ?timeout= query param as seconds, falling
+// back to defaultStreamTimeout in streaming mode or defaultTimeout otherwise
+func parseTimeoutParam(raw string, streaming bool) time.Duration {
+	fallback := defaultTimeout
+	if streaming {
+		fallback = defaultStreamTimeout
+	}
+
+	if raw == "" {
+		return fallback
+	}
+
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// parseProtocolParam reads the ?protocol= query param, defaulting to
+// lib.ProtocolAuto for anything empty or unrecognized
+func parseProtocolParam(raw string) lib.Protocol {
+	switch lib.Protocol(strings.ToLower(raw)) {
+	case lib.ProtocolWhois:
+		return lib.ProtocolWhois
+	case lib.ProtocolRDAP:
+		return lib.ProtocolRDAP
+	default:
+		return lib.ProtocolAuto
+	}
+}
+
+// cacheHeaderValue reports HIT only when every domain in the batch was
+// served from cache, and MISS if any one of them required a live lookup
+func cacheHeaderValue(hits []bool) string {
+	for _, hit := range hits {
+		if !hit {
+			return "MISS"
+		}
+	}
+	return "HIT"
+}
+
 // calculateDomainAge calculates the age of the domain in days based on the creation date
 func calculateDomainAge(createdDate *time.Time) int {
 	if createdDate == nil {
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lissy93/who-dat/lib"
+)
+
+// streamWorkerPoolSize bounds how many domain lookups run concurrently in
+// streaming mode, so a batch of 500+ domains doesn't fan out into 500
+// simultaneous Whois/RDAP connections
+const streamWorkerPoolSize = 20
+
+// streamModeNDJSON and streamModeSSE are the two supported ?stream= values
+const (
+	streamModeNDJSON = "ndjson"
+	streamModeSSE    = "sse"
+)
+
+// streamMultiHandler serves MultiHandler's ?stream=ndjson|sse modes: it
+// flushes each domain's DomainInfo to the client as soon as that domain's
+// lookup completes, instead of buffering the whole batch and marshaling it
+// at the end, so large batches show incremental progress. The whole stream
+// is still bounded by ctx: once it is done, no further results are written.
+func streamMultiHandler(w http.ResponseWriter, ctx context.Context, mode string, domains []string, ttl time.Duration, protocol lib.Protocol) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "streaming not supported by this server")
+		return
+	}
+
+	switch mode {
+	case streamModeSSE:
+		w.Header().Set("Content-Type", "text/event-stream")
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	type result struct {
+		index int
+		info  DomainInfo
+	}
+
+	jobs := make(chan int)
+	// results is buffered to len(domains) so a worker's send never blocks
+	// once the main loop below has stopped reading (e.g. on ctx.Done()) -
+	// otherwise that worker, and its open Whois/RDAP connection, would leak
+	// forever waiting on an unbuffered send nobody is left to receive
+	results := make(chan result, len(domains))
+
+	for workerID := 0; workerID < streamWorkerPoolSize; workerID++ {
+		go func() {
+			for i := range jobs {
+				domain := domains[i]
+				whois, _, err := lib.CachedWhois(ctx, domain, ttl, protocol)
+				if err != nil {
+					whois = lib.WhoisInfo{DomainName: domain, Error: err.Error()}
+				}
+
+				results <- result{index: i, info: DomainInfo{
+					WhoisInfo:       whois,
+					DomainAge:       calculateDomainAge(whois.CreatedDate),
+					DomainRegLength: calculateDomainRegistrationLength(whois.CreatedDate, whois.ExpirationDate),
+					Error:           whois.Error,
+				}}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range domains {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for received := 0; received < len(domains); received++ {
+		select {
+		case <-ctx.Done():
+			return
+		case res := <-results:
+			writeStreamedResult(w, mode, res.info)
+			flusher.Flush()
+		}
+	}
+}
+
+// writeStreamedResult writes a single DomainInfo in the wire format the
+// chosen stream mode expects
+func writeStreamedResult(w http.ResponseWriter, mode string, info DomainInfo) {
+	payload, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+
+	if mode == streamModeSSE {
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+		return
+	}
+
+	w.Write(payload)
+	w.Write([]byte("\n"))
+}
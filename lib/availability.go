@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"context"
+	"strings"
+)
+
+// AvailabilityInfo reports whether a domain is registered, available for
+// registration, or reserved/blocked by its registry
+type AvailabilityInfo struct {
+	Domain    string `json:"domain"`
+	Available bool   `json:"available"`
+	Reserved  bool   `json:"reserved"`
+	Premium   bool   `json:"premium"`
+	RawSignal string `json:"raw_signal,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// noMatchSignals are case-insensitive substrings that Whois servers use,
+// across many different TLDs, to say "this domain isn't registered"
+var noMatchSignals = []string{
+	"no match for",
+	"not found",
+	"no matching record",
+	"domain not found",
+	"no data found",
+	"status: available",
+	"is free",
+	"no object found",
+	"status: free",  // .de
+	"no such domain", // .uk
+	"no match!!",     // .jp
+}
+
+// reservedSignals are substrings indicating a registry-reserved name that
+// can't be registered even though it isn't held by anyone. These must be
+// specific to a reservation status line, not a bare "reserved" - that also
+// matches ordinary boilerplate like "All rights reserved" in the footer of
+// a normal, registered domain's Whois reply, and must not include generic
+// EPP statuses like "clientTransferProhibited" either, since those are the
+// default anti-hijack statuses on ordinary registered domains.
+var reservedSignals = []string{
+	"status: reserved",
+	"domain status: reserved",
+}
+
+// CheckAvailability performs a raw Whois lookup for domain and classifies
+// it as registered, available, or reserved by scanning the response for
+// well-known "no match" phrasing, falling back to "registered" whenever
+// standard fields like Domain Status or Creation Date are present.
+func CheckAvailability(ctx context.Context, domain string) (AvailabilityInfo, error) {
+	server, _ := ServerForDomain(ctx, domain)
+	if server != "" {
+		if err := waitForServer(ctx, server); err != nil {
+			return AvailabilityInfo{}, err
+		}
+	}
+
+	raw, err := whoisQuery(domain, server)
+	if err != nil {
+		return AvailabilityInfo{}, err
+	}
+
+	return classifyAvailability(domain, raw), nil
+}
+
+// classifyAvailability scans a raw Whois reply for the well-known signals in
+// noMatchSignals/reservedSignals and builds the resulting AvailabilityInfo.
+// Split out from CheckAvailability so the classification itself can be
+// tested without a live Whois query.
+func classifyAvailability(domain, raw string) AvailabilityInfo {
+	info := AvailabilityInfo{Domain: domain}
+	lower := strings.ToLower(raw)
+
+	for _, signal := range noMatchSignals {
+		if strings.Contains(lower, signal) {
+			info.Available = true
+			info.RawSignal = signal
+			break
+		}
+	}
+
+	// Standard fields of a registered domain's record outweigh a no-match
+	// phrase found elsewhere in the reply, unless that phrase is itself a
+	// status line such as "Status: AVAILABLE"
+	if info.Available && !strings.HasPrefix(info.RawSignal, "status: ") {
+		for _, field := range []string{"creation date:", "domain status:"} {
+			if strings.Contains(lower, field) {
+				info.Available = false
+				info.RawSignal = ""
+				break
+			}
+		}
+	}
+
+	for _, signal := range reservedSignals {
+		if strings.Contains(lower, signal) {
+			info.Reserved = true
+			info.Available = false
+			info.RawSignal = signal
+			break
+		}
+	}
+
+	if strings.Contains(lower, "premium") {
+		info.Premium = true
+	}
+
+	return info
+}
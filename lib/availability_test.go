@@ -0,0 +1,48 @@
+package lib
+
+import "testing"
+
+func TestClassifyAvailability(t *testing.T) {
+	cases := []struct {
+		name      string
+		raw       string
+		available bool
+		reserved  bool
+		premium   bool
+	}{
+		{
+			name:      "available",
+			raw:       "No match for \"EXAMPLE-AVAILABLE.COM\"",
+			available: true,
+		},
+		{
+			name:     "reserved",
+			raw:      "Domain Status: reserved",
+			reserved: true,
+		},
+		{
+			name: "registered, not a false-positive on boilerplate",
+			raw:  "Domain Name: example.com\nAll rights reserved.",
+		},
+		{
+			name:    "premium",
+			raw:     "Domain Name: example.com\nPremium Domain: true",
+			premium: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			info := classifyAvailability("example.com", c.raw)
+			if info.Available != c.available {
+				t.Errorf("Available = %v, want %v", info.Available, c.available)
+			}
+			if info.Reserved != c.reserved {
+				t.Errorf("Reserved = %v, want %v", info.Reserved, c.reserved)
+			}
+			if info.Premium != c.premium {
+				t.Errorf("Premium = %v, want %v", info.Premium, c.premium)
+			}
+		})
+	}
+}
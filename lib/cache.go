@@ -0,0 +1,117 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheTTLEnv overrides DefaultCacheTTL when set, in seconds
+const DefaultCacheTTLEnv = "WHODAT_CACHE_TTL"
+
+// DefaultCacheTTL is used whenever a caller doesn't specify its own TTL
+// (e.g. via the MultiHandler ?ttl= query param)
+var DefaultCacheTTL = loadDefaultCacheTTL()
+
+func loadDefaultCacheTTL() time.Duration {
+	const fallback = 6 * time.Hour
+
+	raw := os.Getenv(DefaultCacheTTLEnv)
+	if raw == "" {
+		return fallback
+	}
+
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// whoisCacheEntry is a single memoized lookup result
+type whoisCacheEntry struct {
+	info      WhoisInfo
+	expiresAt time.Time
+}
+
+// whoisCache memoizes Whois lookups per lowercased domain for a caller-chosen
+// TTL, collapsing concurrent lookups for the same domain via singleflight so
+// a burst of requests never fans out into multiple upstream Whois queries
+type whoisCache struct {
+	mu      sync.RWMutex
+	entries map[string]whoisCacheEntry
+	group   singleflight.Group
+
+	hits   uint64
+	misses uint64
+}
+
+// DefaultWhoisCache is the process-wide cache used by CachedWhois
+var DefaultWhoisCache = newWhoisCache()
+
+func newWhoisCache() *whoisCache {
+	return &whoisCache{entries: make(map[string]whoisCacheEntry)}
+}
+
+// CachedWhois returns the domain info for domain, looked up via protocol
+// and served from the cache when a fresh-enough entry exists, otherwise
+// performing (and caching) a live lookup. The returned bool reports
+// whether the result was a cache hit. ttl <= 0 falls back to
+// DefaultCacheTTL. Different protocols are cached under separate keys,
+// since an "auto" lookup and an explicit "rdap" lookup for the same
+// domain can yield differently-shaped results.
+func CachedWhois(ctx context.Context, domain string, ttl time.Duration, protocol Protocol) (WhoisInfo, bool, error) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	key := string(protocol) + ":" + strings.ToLower(domain)
+
+	if info, ok := DefaultWhoisCache.lookup(key); ok {
+		atomic.AddUint64(&DefaultWhoisCache.hits, 1)
+		return info, true, nil
+	}
+
+	result, err, _ := DefaultWhoisCache.group.Do(key, func() (interface{}, error) {
+		return GetDomainInfo(ctx, domain, protocol)
+	})
+	atomic.AddUint64(&DefaultWhoisCache.misses, 1)
+	if err != nil {
+		return WhoisInfo{}, false, err
+	}
+
+	info := result.(WhoisInfo)
+	DefaultWhoisCache.store(key, info, ttl)
+	return info, false, nil
+}
+
+// lookup returns the cached entry for key if it exists and hasn't expired
+func (c *whoisCache) lookup(key string) (WhoisInfo, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return WhoisInfo{}, false
+	}
+	return entry.info, true
+}
+
+// store saves info under key with the given ttl
+func (c *whoisCache) store(key string, info WhoisInfo, ttl time.Duration) {
+	c.mu.Lock()
+	c.entries[key] = whoisCacheEntry{info: info, expiresAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+}
+
+// CacheStats returns the cumulative hit/miss counts for DefaultWhoisCache,
+// surfaced on the metrics endpoint as whodat_cache_hits_total / _misses_total
+func CacheStats() (hits, misses uint64) {
+	return atomic.LoadUint64(&DefaultWhoisCache.hits), atomic.LoadUint64(&DefaultWhoisCache.misses)
+}
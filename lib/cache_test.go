@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWhoisCacheStoreAndLookup(t *testing.T) {
+	c := newWhoisCache()
+	info := WhoisInfo{DomainName: "example.com"}
+
+	c.store("whois:example.com", info, time.Minute)
+
+	got, ok := c.lookup("whois:example.com")
+	if !ok {
+		t.Fatal("lookup() reported a miss right after store()")
+	}
+	if got.DomainName != info.DomainName {
+		t.Errorf("lookup() = %+v, want %+v", got, info)
+	}
+}
+
+func TestWhoisCacheExpiry(t *testing.T) {
+	c := newWhoisCache()
+	c.store("whois:example.com", WhoisInfo{DomainName: "example.com"}, -time.Second)
+
+	if _, ok := c.lookup("whois:example.com"); ok {
+		t.Error("lookup() returned a hit for an entry whose ttl already elapsed")
+	}
+}
+
+func TestWhoisCacheLookupMiss(t *testing.T) {
+	c := newWhoisCache()
+	if _, ok := c.lookup("whois:never-stored.com"); ok {
+		t.Error("lookup() returned a hit for a key that was never stored")
+	}
+}
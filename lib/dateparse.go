@@ -0,0 +1,46 @@
+package lib
+
+import (
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// dateLayouts are the Whois date formats we've seen in the wild, tried in
+// order before falling back to the generic any-format parser
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	"2006-01-02T15:04:05-0700",
+	"20060102",
+	"2006-01-02",
+	"2006-01-02 15:04:05-07",
+	"2006.01.02 15:04:05",
+	"02-Jan-2006",
+	"2006/01/02",
+	"02-01-2006",
+}
+
+// parseWhoisDate tries every known Whois date layout in turn, then falls
+// back to dateparse's generic any-format parser. It returns the parsed
+// time and the name of the layout that matched ("dateparse" for the
+// fallback), or nil and an empty layout if nothing could parse it.
+func parseWhoisDate(raw string) (*time.Time, string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, ""
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, layout
+		}
+	}
+
+	if t, err := dateparse.ParseAny(raw); err == nil {
+		return &t, "dateparse"
+	}
+
+	return nil, ""
+}
@@ -0,0 +1,68 @@
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhoisDate(t *testing.T) {
+	cases := []struct {
+		name       string
+		raw        string
+		wantLayout string
+		want       time.Time
+	}{
+		{
+			name:       "RFC3339",
+			raw:        "2021-04-29T16:20:00Z",
+			wantLayout: time.RFC3339,
+			want:       time.Date(2021, 4, 29, 16, 20, 0, 0, time.UTC),
+		},
+		{
+			name:       "date only",
+			raw:        "2021-04-29",
+			wantLayout: "2006-01-02",
+			want:       time.Date(2021, 4, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "compact",
+			raw:        "20210429",
+			wantLayout: "20060102",
+			want:       time.Date(2021, 4, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "day-month-year with low day/month",
+			raw:        "05-11-2020",
+			wantLayout: "02-01-2006",
+			want:       time.Date(2020, 11, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "dotted with time",
+			raw:        "2021.04.29 16:20:00",
+			wantLayout: "2006.01.02 15:04:05",
+			want:       time.Date(2021, 4, 29, 16, 20, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, layout := parseWhoisDate(tc.raw)
+			if got == nil {
+				t.Fatalf("parseWhoisDate(%q) = nil, want %v", tc.raw, tc.want)
+			}
+			if layout != tc.wantLayout {
+				t.Errorf("parseWhoisDate(%q) layout = %q, want %q", tc.raw, layout, tc.wantLayout)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("parseWhoisDate(%q) = %v, want %v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseWhoisDateEmpty(t *testing.T) {
+	got, layout := parseWhoisDate("")
+	if got != nil || layout != "" {
+		t.Errorf("parseWhoisDate(\"\") = %v, %q, want nil, \"\"", got, layout)
+	}
+}
@@ -0,0 +1,41 @@
+package lib
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// serverRateLimit is deliberately conservative: registries like VeriSign
+// and DENIC are quick to temporarily block clients that query too fast
+const (
+	serverRateLimitPerSecond = 0.5 // one query every two seconds
+	serverRateLimitBurst     = 2
+)
+
+// serverLimiters holds one token bucket per Whois server hostname, created
+// lazily the first time that server is queried
+var serverLimiters = struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}{limiters: make(map[string]*rate.Limiter)}
+
+// waitForServer blocks until the token bucket for server allows another
+// query, or ctx is cancelled first
+func waitForServer(ctx context.Context, server string) error {
+	return limiterFor(server).Wait(ctx)
+}
+
+// limiterFor returns the token bucket for server, creating it if needed
+func limiterFor(server string) *rate.Limiter {
+	serverLimiters.mu.Lock()
+	defer serverLimiters.mu.Unlock()
+
+	limiter, ok := serverLimiters.limiters[server]
+	if !ok {
+		limiter = rate.NewLimiter(serverRateLimitPerSecond, serverRateLimitBurst)
+		serverLimiters.limiters[server] = limiter
+	}
+	return limiter
+}
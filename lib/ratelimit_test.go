@@ -0,0 +1,25 @@
+package lib
+
+import "testing"
+
+func TestLimiterForReusesInstancePerServer(t *testing.T) {
+	a := limiterFor("whois.ratelimit-test-a.example")
+	b := limiterFor("whois.ratelimit-test-a.example")
+	if a != b {
+		t.Error("limiterFor() returned different instances for the same server")
+	}
+
+	c := limiterFor("whois.ratelimit-test-b.example")
+	if a == c {
+		t.Error("limiterFor() returned the same instance for different servers")
+	}
+}
+
+func TestLimiterForAllowsBurst(t *testing.T) {
+	limiter := limiterFor("whois.ratelimit-test-burst.example")
+	for i := 0; i < serverRateLimitBurst; i++ {
+		if !limiter.Allow() {
+			t.Fatalf("Allow() returned false within the configured burst (call %d)", i+1)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ianaRDAPBootstrapURL is IANA's RDAP bootstrap registry (RFC 7484),
+// mapping TLDs to the RDAP base URLs that serve them
+const ianaRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+// ErrRDAPNotFound is returned by GetRDAP when the registry has no RDAP
+// record for the domain (HTTP 404), which callers in "auto" mode treat as
+// a signal to fall back to legacy Whois rather than a hard failure
+var ErrRDAPNotFound = errors.New("rdap: domain not found")
+
+// rdapBootstrapFile is the shape of data.iana.org/rdap/dns.json
+type rdapBootstrapFile struct {
+	Services [][][]string `json:"services"`
+}
+
+// rdapResponse is the subset of RFC 7483's domain object we care about
+type rdapResponse struct {
+	LDHName     string        `json:"ldhName"`
+	Status      []string      `json:"status"`
+	Nameservers []rdapNS      `json:"nameservers"`
+	Events      []rdapEvent   `json:"events"`
+	Entities    []rdapEntity  `json:"entities"`
+}
+
+type rdapNS struct {
+	LDHName string `json:"ldhName"`
+}
+
+type rdapEvent struct {
+	EventAction string `json:"eventAction"`
+	EventDate   string `json:"eventDate"`
+}
+
+type rdapEntity struct {
+	Roles      []string      `json:"roles"`
+	VCardArray []interface{} `json:"vcardArray"`
+}
+
+var rdapBootstrap = struct {
+	mu      sync.RWMutex
+	servers map[string]string
+	loaded  bool
+}{servers: make(map[string]string)}
+
+// rdapServerForTLD returns the RDAP base URL for tld, fetching and caching
+// the IANA bootstrap registry on first use
+func rdapServerForTLD(ctx context.Context, tld string) (string, error) {
+	rdapBootstrap.mu.RLock()
+	server, ok := rdapBootstrap.servers[tld]
+	loaded := rdapBootstrap.loaded
+	rdapBootstrap.mu.RUnlock()
+	if ok {
+		return server, nil
+	}
+	if loaded {
+		return "", fmt.Errorf("no rdap server known for .%s", tld)
+	}
+
+	if err := loadRDAPBootstrap(ctx); err != nil {
+		return "", err
+	}
+
+	rdapBootstrap.mu.RLock()
+	defer rdapBootstrap.mu.RUnlock()
+	server, ok = rdapBootstrap.servers[tld]
+	if !ok {
+		return "", fmt.Errorf("no rdap server known for .%s", tld)
+	}
+	return server, nil
+}
+
+// loadRDAPBootstrap fetches and parses the IANA RDAP bootstrap registry
+func loadRDAPBootstrap(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ianaRDAPBootstrapURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching rdap bootstrap registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var file rdapBootstrapFile
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return fmt.Errorf("decoding rdap bootstrap registry: %w", err)
+	}
+
+	servers := make(map[string]string)
+	for _, service := range file.Services {
+		if len(service) != 2 {
+			continue
+		}
+		tlds, urls := service[0], service[1]
+		if len(urls) == 0 {
+			continue
+		}
+		for _, tld := range tlds {
+			servers[strings.ToLower(tld)] = strings.TrimRight(urls[0], "/")
+		}
+	}
+
+	rdapBootstrap.mu.Lock()
+	rdapBootstrap.servers = servers
+	rdapBootstrap.loaded = true
+	rdapBootstrap.mu.Unlock()
+
+	return nil
+}
+
+// GetRDAP looks up domain via RDAP (RFC 7482/7483), mapping the JSON
+// response into the same WhoisInfo shape GetWhois produces so API
+// consumers see a stable struct regardless of which protocol answered
+func GetRDAP(ctx context.Context, domain string) (WhoisInfo, error) {
+	tld := tldOf(domain)
+	base, err := rdapServerForTLD(ctx, tld)
+	if err != nil {
+		return WhoisInfo{}, err
+	}
+
+	url := fmt.Sprintf("%s/domain/%s", base, domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return WhoisInfo{}, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return WhoisInfo{}, fmt.Errorf("rdap lookup for %s failed: %w", domain, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return WhoisInfo{}, ErrRDAPNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return WhoisInfo{}, fmt.Errorf("rdap lookup for %s returned status %d", domain, resp.StatusCode)
+	}
+
+	var parsed rdapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return WhoisInfo{}, fmt.Errorf("rdap decode for %s failed: %w", domain, err)
+	}
+
+	return mapRDAPResponse(domain, base, parsed), nil
+}
+
+// mapRDAPResponse converts an RDAP domain object into our WhoisInfo shape
+func mapRDAPResponse(domain, whoisServer string, parsed rdapResponse) WhoisInfo {
+	info := WhoisInfo{
+		DomainName:  domain,
+		WhoisServer: whoisServer,
+		Status:      parsed.Status,
+	}
+
+	for _, ns := range parsed.Nameservers {
+		if ns.LDHName != "" {
+			info.NameServers = append(info.NameServers, ns.LDHName)
+		}
+	}
+
+	for _, event := range parsed.Events {
+		switch strings.ToLower(event.EventAction) {
+		case "registration":
+			info.RawCreatedDate = event.EventDate
+			info.CreatedDate, info.CreatedDateLayout = parseWhoisDate(event.EventDate)
+		case "expiration":
+			info.RawExpirationDate = event.EventDate
+			info.ExpirationDate, info.ExpirationDateLayout = parseWhoisDate(event.EventDate)
+		case "last changed", "last update of rdap database":
+			info.RawUpdatedDate = event.EventDate
+			info.UpdatedDate, info.UpdatedDateLayout = parseWhoisDate(event.EventDate)
+		}
+	}
+
+	for _, entity := range parsed.Entities {
+		if !hasRole(entity.Roles, "registrar") {
+			continue
+		}
+		if name := vCardFN(entity.VCardArray); name != "" {
+			info.Registrar = name
+		}
+	}
+
+	return info
+}
+
+// hasRole reports whether roles contains role, case-insensitively
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// vCardFN extracts the "fn" (formatted name) property from an RDAP
+// vCardArray, which takes the jCard form ["vcard", [["fn", {}, "text", "..."], ...]]
+func vCardFN(vCardArray []interface{}) string {
+	if len(vCardArray) != 2 {
+		return ""
+	}
+	properties, ok := vCardArray[1].([]interface{})
+	if !ok {
+		return ""
+	}
+
+	for _, property := range properties {
+		fields, ok := property.([]interface{})
+		if !ok || len(fields) < 4 {
+			continue
+		}
+		name, ok := fields[0].(string)
+		if !ok || !strings.EqualFold(name, "fn") {
+			continue
+		}
+		if value, ok := fields[3].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/likexian/whois"
+	"golang.org/x/sync/singleflight"
+)
+
+// ianaRootServer is IANA's root Whois bootstrap: querying it for a bare TLD
+// returns a "whois: <server>" referral line pointing at that TLD's registry
+const ianaRootServer = "whois.iana.org"
+
+// ianaWhoisReferralRe pulls the referral server out of an IANA root reply
+var ianaWhoisReferralRe = regexp.MustCompile(`(?im)^whois:\s*(\S+)`)
+
+// tldServers caches TLD -> authoritative Whois server lookups for the
+// lifetime of the process, since IANA's registry delegations change rarely
+var tldServers = struct {
+	mu      sync.RWMutex
+	servers map[string]string
+}{servers: make(map[string]string)}
+
+// tldServerGroup collapses concurrent first-time lookups for the same TLD
+// into a single IANA bootstrap query, the same way DefaultWhoisCache
+// collapses concurrent domain lookups - otherwise a batch of same-TLD
+// domains that all miss the cache at once (e.g. a brand new TLD) would each
+// fire their own concurrent query at whois.iana.org
+var tldServerGroup singleflight.Group
+
+// ServerForDomain returns the authoritative Whois server for domain's TLD,
+// resolving it via the IANA root Whois bootstrap and caching the result.
+// The bootstrap query itself is rate-limited and singleflighted per TLD,
+// since whois.iana.org is just another Whois server that will throttle a
+// bursty client.
+func ServerForDomain(ctx context.Context, domain string) (string, error) {
+	tld := tldOf(domain)
+	if tld == "" {
+		return "", fmt.Errorf("could not determine TLD for domain %q", domain)
+	}
+
+	if server, ok := cachedTLDServer(tld); ok {
+		return server, nil
+	}
+
+	result, err, _ := tldServerGroup.Do(tld, func() (interface{}, error) {
+		// Re-check now that we hold the singleflight slot - another
+		// goroutine may have just finished resolving this TLD.
+		if server, ok := cachedTLDServer(tld); ok {
+			return server, nil
+		}
+
+		if err := waitForServer(ctx, ianaRootServer); err != nil {
+			return "", fmt.Errorf("rate limit wait for %s failed: %w", ianaRootServer, err)
+		}
+
+		raw, err := whois.Whois(tld, ianaRootServer)
+		if err != nil {
+			return "", fmt.Errorf("iana bootstrap lookup for .%s failed: %w", tld, err)
+		}
+
+		match := ianaWhoisReferralRe.FindStringSubmatch(raw)
+		if match == nil {
+			return "", fmt.Errorf("no whois referral found for .%s in iana bootstrap response", tld)
+		}
+		server := match[1]
+
+		tldServers.mu.Lock()
+		tldServers.servers[tld] = server
+		tldServers.mu.Unlock()
+
+		return server, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.(string), nil
+}
+
+// cachedTLDServer returns the cached server for tld, if any
+func cachedTLDServer(tld string) (string, bool) {
+	tldServers.mu.RLock()
+	defer tldServers.mu.RUnlock()
+	server, ok := tldServers.servers[tld]
+	return server, ok
+}
+
+// tldOf returns the lowercased top-level label of domain, e.g. "com" for
+// "example.com" or "co.uk" style domains returns just "uk"
+func tldOf(domain string) string {
+	labels := strings.Split(strings.Trim(strings.ToLower(domain), "."), ".")
+	if len(labels) == 0 {
+		return ""
+	}
+	return labels[len(labels)-1]
+}
@@ -0,0 +1,40 @@
+package lib
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTldOf(t *testing.T) {
+	cases := map[string]string{
+		"example.com":    "com",
+		"EXAMPLE.COM":    "com",
+		"example.co.uk":  "uk",
+		"example.com.":   "com",
+		"":               "",
+		"no-dots-at-all": "no-dots-at-all",
+	}
+
+	for domain, want := range cases {
+		if got := tldOf(domain); got != want {
+			t.Errorf("tldOf(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
+
+func TestServerForDomainCacheHit(t *testing.T) {
+	const tld = "test-tld-cache-hit"
+	const server = "whois.example-registry.test"
+
+	tldServers.mu.Lock()
+	tldServers.servers[tld] = server
+	tldServers.mu.Unlock()
+
+	got, err := ServerForDomain(context.Background(), "example."+tld)
+	if err != nil {
+		t.Fatalf("ServerForDomain() returned an error after priming the cache: %v", err)
+	}
+	if got != server {
+		t.Errorf("ServerForDomain() = %q, want %q", got, server)
+	}
+}
@@ -0,0 +1,36 @@
+package lib
+
+import "time"
+
+// WhoisInfo represents the parsed Whois information for a single domain
+type WhoisInfo struct {
+	DomainName     string     `json:"domain_name,omitempty"`
+	Registrar      string     `json:"registrar,omitempty"`
+	WhoisServer    string     `json:"whois_server,omitempty"`
+	UpdatedDate    *time.Time `json:"updated_date,omitempty"`
+	CreatedDate    *time.Time `json:"created_date,omitempty"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	NameServers    []string   `json:"name_servers,omitempty"`
+	Status         []string   `json:"status,omitempty"`
+	Emails         []string   `json:"emails,omitempty"`
+	DNSSEC         string     `json:"dnssec,omitempty"`
+
+	// RawCreatedDate, RawUpdatedDate and RawExpirationDate are the
+	// unparsed date strings as returned by the Whois server, kept
+	// around so callers can see what parseWhoisDate was given
+	RawCreatedDate    string `json:"raw_created_date,omitempty"`
+	RawUpdatedDate    string `json:"raw_updated_date,omitempty"`
+	RawExpirationDate string `json:"raw_expiration_date,omitempty"`
+
+	// CreatedDateLayout, UpdatedDateLayout and ExpirationDateLayout name
+	// the layout that parseWhoisDate matched for the corresponding date,
+	// or "dateparse" if only the generic fallback parser succeeded
+	CreatedDateLayout    string `json:"created_date_layout,omitempty"`
+	UpdatedDateLayout    string `json:"updated_date_layout,omitempty"`
+	ExpirationDateLayout string `json:"expiration_date_layout,omitempty"`
+
+	// Error is set instead of aborting a batch lookup when this domain's
+	// own Whois query was throttled or failed; the rest of the batch
+	// still completes normally
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,222 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/likexian/whois"
+	whoisparser "github.com/likexian/whois-parser"
+)
+
+// registrarWhoisServerRe extracts a referral server from a thin registry's
+// reply, e.g. "Registrar WHOIS Server: whois.registrar.example"
+var registrarWhoisServerRe = regexp.MustCompile(`(?i)Registrar WHOIS Server: (.*)`)
+
+// GetWhois performs a Whois lookup for a single domain and parses the
+// result. It is a convenience wrapper around GetWhoisContext for callers
+// that don't need cancellation.
+func GetWhois(domain string) (WhoisInfo, error) {
+	return GetWhoisContext(context.Background(), domain)
+}
+
+// GetWhoisContext performs a Whois lookup for a single domain, routing the
+// query to the authoritative server for the domain's TLD (via
+// ServerForDomain) and respecting that server's rate limit. Thin registries
+// (notably .com/.net via Verisign) often only return the registrar referral
+// on the first query and the expiration date itself only shows up once that
+// referral is queried, so GetWhoisContext retries once against the
+// registrar's own server when that happens.
+func GetWhoisContext(ctx context.Context, domain string) (WhoisInfo, error) {
+	server, err := ServerForDomain(ctx, domain)
+	if err != nil {
+		// Fall back to the library's built-in defaults rather than failing
+		// outright - not every TLD is in the IANA bootstrap cache yet.
+		server = ""
+	}
+
+	if server != "" {
+		if err := waitForServer(ctx, server); err != nil {
+			return WhoisInfo{}, fmt.Errorf("rate limit wait for %s failed: %w", server, err)
+		}
+	}
+
+	raw, err := whoisQuery(domain, server)
+	if err != nil {
+		return WhoisInfo{}, fmt.Errorf("whois lookup for %s failed: %w", domain, err)
+	}
+
+	parsed, err := whoisparser.Parse(raw)
+	if err != nil {
+		return WhoisInfo{}, fmt.Errorf("whois parse for %s failed: %w", domain, err)
+	}
+
+	info := mapParsedWhois(domain, raw, parsed)
+
+	if info.ExpirationDate == nil {
+		if retried, ok := retryAgainstReferral(domain, raw); ok {
+			info = retried
+		}
+	}
+
+	return info, nil
+}
+
+// Protocol selects which wire protocol GetDomainInfo uses to look up a domain
+type Protocol string
+
+const (
+	ProtocolAuto  Protocol = "auto"
+	ProtocolWhois Protocol = "whois"
+	ProtocolRDAP  Protocol = "rdap"
+)
+
+// GetDomainInfo looks up domain using the requested protocol. ProtocolAuto
+// (the default) tries RDAP first, since it returns structured JSON over
+// HTTPS, and falls back to legacy Whois if the registry has no RDAP record
+// for the TLD or the RDAP request itself fails.
+func GetDomainInfo(ctx context.Context, domain string, protocol Protocol) (WhoisInfo, error) {
+	switch protocol {
+	case ProtocolRDAP:
+		return GetRDAP(ctx, domain)
+	case ProtocolWhois:
+		return GetWhoisContext(ctx, domain)
+	default:
+		if info, err := GetRDAP(ctx, domain); err == nil {
+			return info, nil
+		}
+		return GetWhoisContext(ctx, domain)
+	}
+}
+
+// whoisQuery runs the underlying port-43 query, using the explicit
+// authoritative server when we have one and otherwise letting the
+// underlying library pick a default
+func whoisQuery(domain, server string) (string, error) {
+	if server == "" {
+		return whois.Whois(domain)
+	}
+	return whois.Whois(domain, server)
+}
+
+// retryAgainstReferral re-queries a domain against whichever server the
+// first reply pointed us at, trying the "Registrar WHOIS Server" referral
+// first and a "= domain" extended query second, since some thick registries
+// only return the full record in response to that form.
+func retryAgainstReferral(domain, firstRaw string) (WhoisInfo, bool) {
+	if match := registrarWhoisServerRe.FindStringSubmatch(firstRaw); len(match) == 2 {
+		server := match[1]
+		if raw, err := whois.Whois(domain, server); err == nil {
+			if parsed, err := whoisparser.Parse(raw); err == nil {
+				return mapParsedWhois(domain, raw, parsed), true
+			}
+		}
+	}
+
+	if raw, err := whois.Whois("= " + domain); err == nil {
+		if parsed, err := whoisparser.Parse(raw); err == nil {
+			return mapParsedWhois(domain, raw, parsed), true
+		}
+	}
+
+	return WhoisInfo{}, false
+}
+
+// GetMultiWhoisCached is GetMultiWhois but served through CachedWhois, so
+// repeat lookups within ttl are answered without hitting the Whois server.
+// It returns one hit/miss bool per domain alongside the results. A failed
+// or throttled lookup for one domain is recorded in that domain's
+// WhoisInfo.Error rather than aborting the rest of the batch.
+//
+// This always waits for every goroutine to finish writing its slot before
+// returning - ctx cancellation is still visible via the returned error, but
+// it only cancels the individual lookups (each one observes ctx itself via
+// waitForServer/CachedWhois), not the bookkeeping around results, so there's
+// no race between an early return and in-flight writes to results/hits.
+func GetMultiWhoisCached(ctx context.Context, domains []string, ttl time.Duration, protocol Protocol) ([]WhoisInfo, []bool, error) {
+	results := make([]WhoisInfo, len(domains))
+	hits := make([]bool, len(domains))
+
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func(i int, domain string) {
+			defer wg.Done()
+
+			info, hit, err := CachedWhois(ctx, domain, ttl, protocol)
+			if err != nil {
+				info = WhoisInfo{DomainName: domain, Error: err.Error()}
+			}
+			results[i] = info
+			hits[i] = hit
+		}(i, domain)
+	}
+	wg.Wait()
+
+	return results, hits, ctx.Err()
+}
+
+// GetMultiWhois performs Whois lookups for a batch of domains concurrently,
+// returning one WhoisInfo per domain in the same order they were requested.
+// A failed or throttled lookup for one domain is recorded in that domain's
+// WhoisInfo.Error rather than aborting the rest of the batch.
+//
+// This always waits for every goroutine to finish writing its slot before
+// returning, so there's no race between an early ctx-cancelled return and
+// in-flight writes to results; ctx cancellation still surfaces via the
+// returned error once every lookup has returned. A lookup observes ctx only
+// while resolving the server and waiting on the rate limiter; the port-43
+// query itself is not cancelled by ctx.
+func GetMultiWhois(ctx context.Context, domains []string) ([]WhoisInfo, error) {
+	results := make([]WhoisInfo, len(domains))
+
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func(i int, domain string) {
+			defer wg.Done()
+
+			info, err := GetWhoisContext(ctx, domain)
+			if err != nil {
+				info = WhoisInfo{DomainName: domain, Error: err.Error()}
+			}
+			results[i] = info
+		}(i, domain)
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// mapParsedWhois converts a whois-parser result into our stable WhoisInfo
+// shape, parsing dates ourselves via parseWhoisDate rather than trusting
+// whois-parser's own best-effort time conversion
+func mapParsedWhois(domain, raw string, parsed whoisparser.WhoisInfo) WhoisInfo {
+	info := WhoisInfo{
+		DomainName: domain,
+	}
+
+	if parsed.Registrar != nil {
+		info.Registrar = parsed.Registrar.Name
+	}
+
+	if parsed.Domain != nil {
+		info.WhoisServer = parsed.Domain.WhoisServer
+		info.DNSSEC = strconv.FormatBool(parsed.Domain.DNSSec)
+		info.NameServers = parsed.Domain.NameServers
+		info.Status = parsed.Domain.Status
+
+		info.RawCreatedDate = parsed.Domain.CreatedDate
+		info.RawUpdatedDate = parsed.Domain.UpdatedDate
+		info.RawExpirationDate = parsed.Domain.ExpirationDate
+
+		info.CreatedDate, info.CreatedDateLayout = parseWhoisDate(parsed.Domain.CreatedDate)
+		info.UpdatedDate, info.UpdatedDateLayout = parseWhoisDate(parsed.Domain.UpdatedDate)
+		info.ExpirationDate, info.ExpirationDateLayout = parseWhoisDate(parsed.Domain.ExpirationDate)
+	}
+
+	return info
+}